@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AuditOptions configures RunPSSAudit.
+type AuditOptions struct {
+	// Namespaces selects which namespaces are audited; see NamespaceSelector.
+	Namespaces NamespaceSelector
+	// LabelSelector and FieldSelector are passed through to each pod List
+	// call, restricting the audit to matching pods. Empty matches everything.
+	LabelSelector string
+	FieldSelector string
+}
+
+// RunPSSAudit evaluates every pod selected by opts against profile and
+// publishes the resulting Findings via reporter. Unlike
+// findContainersWithErrors, this never execs into a container — it's a
+// pure spec audit, so it also works against clusters without exec RBAC.
+func RunPSSAudit(ctx context.Context, clientset kubernetes.Interface, profile PSSProfile, reporter Reporter, opts AuditOptions) error {
+	namespaces, err := resolveNamespaces(ctx, clientset, opts.Namespaces)
+	if err != nil {
+		return err
+	}
+
+	var findings []Finding
+	for _, namespace := range namespaces {
+		continueToken := ""
+		for {
+			podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+				Limit:         podListPageSize,
+				Continue:      continueToken,
+				LabelSelector: opts.LabelSelector,
+				FieldSelector: opts.FieldSelector,
+			})
+			if err != nil {
+				return err
+			}
+
+			for i := range podList.Items {
+				findings = append(findings, evaluatePSSPod(&podList.Items[i], profile)...)
+			}
+
+			continueToken = podList.Continue
+			if continueToken == "" {
+				break
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	return reporter.Report(findings)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// cliFlags holds every flag newRootCmd registers, bound via cobra's
+// pointer-based flag APIs.
+type cliFlags struct {
+	kubeconfig    string
+	kubeContext   string
+	namespaces    []string
+	excludeNS     []string
+	allNamespaces bool
+	labelSelector string
+	fieldSelector string
+	timeout       time.Duration
+
+	mode              string
+	concurrency       int
+	useEphemeralDebug bool
+
+	profile string
+	output  string
+
+	watch       bool
+	metricsAddr string
+	resync      time.Duration
+}
+
+// newRootCmd builds the root-detector CLI: a one-shot root-detection scan
+// by default, a continuous root-detection watch with --watch, or a one-shot
+// Pod Security Standards audit with --profile. --profile is not applied in
+// --watch mode.
+func newRootCmd() *cobra.Command {
+	flags := &cliFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "root-detector",
+		Short: "Detect containers running as root and audit Pod Security Standards",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoot(cmd.Context(), flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.kubeconfig, "kubeconfig", "", "Path to a kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+	cmd.Flags().StringVar(&flags.kubeContext, "context", "", "kubeconfig context to use (defaults to the current context)")
+	cmd.Flags().StringArrayVar(&flags.namespaces, "namespace", nil, "Namespace to scan (repeatable); defaults to all namespaces")
+	cmd.Flags().StringArrayVar(&flags.excludeNS, "exclude-namespace", defaultExcludeNamespaces, "Namespace to exclude (repeatable); ignored when --namespace or --all-namespaces is set")
+	cmd.Flags().BoolVar(&flags.allNamespaces, "all-namespaces", false, "Scan every namespace, including --exclude-namespace defaults")
+	cmd.Flags().StringVar(&flags.labelSelector, "label-selector", "", "Only scan pods matching this label selector")
+	cmd.Flags().StringVar(&flags.fieldSelector, "field-selector", "", "Only scan pods matching this field selector")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 0, "Deadline for the entire scan; 0 means no deadline")
+
+	cmd.Flags().StringVar(&flags.mode, "mode", string(DetectModeHybrid), "Root-detection mode: exec, spec, or hybrid")
+	cmd.Flags().IntVar(&flags.concurrency, "concurrency", 0, "Worker-pool size for concurrent container checks; 0 uses a default scaled to CPU count")
+	cmd.Flags().BoolVar(&flags.useEphemeralDebug, "use-ephemeral-debug", false, "Fall back to an ephemeral debug container for images with no shell")
+
+	cmd.Flags().StringVar(&flags.profile, "profile", "", "Evaluate pods against a Pod Security Standards profile (baseline or restricted) instead of root-detection")
+	cmd.Flags().StringVar(&flags.output, "output", "text", "Finding output format: text, json, or sarif")
+
+	cmd.Flags().BoolVar(&flags.watch, "watch", false, "Watch the cluster continuously instead of scanning once")
+	cmd.Flags().StringVar(&flags.metricsAddr, "metrics-addr", "", "Address to serve Prometheus metrics on in --watch mode, e.g. :8080")
+	cmd.Flags().DurationVar(&flags.resync, "resync", defaultResyncPeriod, "Informer resync period in --watch mode")
+
+	return cmd
+}
+
+// runRoot authenticates to the cluster and dispatches to the requested
+// mode: watch, PSS audit, or a one-shot root-detection scan.
+func runRoot(ctx context.Context, flags *cliFlags) error {
+	if flags.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flags.timeout)
+		defer cancel()
+	}
+
+	clientset, config, err := authenticateToCluster(flags.kubeconfig, flags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("authenticating to cluster: %w", err)
+	}
+
+	namespaces := namespaceSelectorFromFlags(flags)
+
+	reporter, err := reporterFromFlags(flags)
+	if err != nil {
+		return err
+	}
+
+	if flags.watch {
+		watchOpts := DefaultWatchOptions()
+		watchOpts.Mode = DetectMode(flags.mode)
+		watchOpts.UseEphemeralDebug = flags.useEphemeralDebug
+		watchOpts.Reporter = reporter
+		watchOpts.ResyncPeriod = flags.resync
+		watchOpts.MetricsAddr = flags.metricsAddr
+		watchOpts.LabelSelector = flags.labelSelector
+		watchOpts.FieldSelector = flags.fieldSelector
+		if !flags.allNamespaces {
+			if len(flags.namespaces) == 1 {
+				watchOpts.Namespace = flags.namespaces[0]
+			} else if len(flags.namespaces) > 1 {
+				watchOpts.IncludeNamespaces = flags.namespaces
+			} else {
+				watchOpts.ExcludeNamespaces = flags.excludeNS
+			}
+		}
+		return RunWatch(ctx, clientset, config, watchOpts)
+	}
+
+	if flags.profile != "" {
+		profile, err := pssProfileFromFlag(flags.profile)
+		if err != nil {
+			return err
+		}
+		auditOpts := AuditOptions{
+			Namespaces:    namespaces,
+			LabelSelector: flags.labelSelector,
+			FieldSelector: flags.fieldSelector,
+		}
+		return RunPSSAudit(ctx, clientset, profile, reporter, auditOpts)
+	}
+
+	scanOpts := DefaultScanOptions()
+	scanOpts.Mode = DetectMode(flags.mode)
+	scanOpts.Concurrency = flags.concurrency
+	scanOpts.UseEphemeralDebug = flags.useEphemeralDebug
+	scanOpts.Namespaces = namespaces
+	scanOpts.LabelSelector = flags.labelSelector
+	scanOpts.FieldSelector = flags.fieldSelector
+
+	rootContainers, errorContainers, err := findContainersWithErrors(ctx, clientset, config, scanOpts)
+	if err != nil {
+		return fmt.Errorf("scanning cluster: %w", err)
+	}
+
+	if err := reporter.Report(rootContainers); err != nil {
+		return fmt.Errorf("reporting findings: %w", err)
+	}
+	for _, errored := range errorContainers {
+		fmt.Printf("Error checking %s/%s/%s after %d attempt(s): %s\n", errored.Namespace, errored.PodName, errored.Container, errored.ExecAttempts, errored.LastExecError)
+	}
+
+	return nil
+}
+
+// namespaceSelectorFromFlags turns --namespace/--exclude-namespace/
+// --all-namespaces into a NamespaceSelector. --all-namespaces passes a
+// non-nil, empty Exclude so resolveNamespaces doesn't fall back to
+// defaultExcludeNamespaces.
+func namespaceSelectorFromFlags(flags *cliFlags) NamespaceSelector {
+	if flags.allNamespaces {
+		return NamespaceSelector{Exclude: []string{}}
+	}
+	return NamespaceSelector{
+		Namespaces: flags.namespaces,
+		Exclude:    flags.excludeNS,
+	}
+}
+
+// pssProfileFromFlag validates --profile against the known PSS profiles, so
+// a typo can't silently fall back to a weaker (baseline-only) audit.
+func pssProfileFromFlag(profile string) (PSSProfile, error) {
+	switch PSSProfile(profile) {
+	case PSSProfileBaseline, PSSProfileRestricted:
+		return PSSProfile(profile), nil
+	default:
+		return "", fmt.Errorf("unknown --profile %q: want baseline or restricted", profile)
+	}
+}
+
+// reporterFromFlags builds the Reporter matching --output.
+func reporterFromFlags(flags *cliFlags) (Reporter, error) {
+	switch flags.output {
+	case "", "text":
+		return StdoutReporter{Writer: os.Stdout}, nil
+	case "json":
+		return JSONReporter{Writer: os.Stdout}, nil
+	case "sarif":
+		return SARIFReporter{Writer: os.Stdout}, nil
+	default:
+		return nil, fmt.Errorf("unknown --output %q: want text, json, or sarif", flags.output)
+	}
+}
+
+func main() {
+	if err := newRootCmd().ExecuteContext(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceSelectorFromFlags(t *testing.T) {
+	testCases := []struct {
+		name  string
+		flags *cliFlags
+		want  NamespaceSelector
+	}{
+		{
+			name:  "all-namespaces excludes nothing",
+			flags: &cliFlags{allNamespaces: true, excludeNS: []string{"kube-system"}},
+			want:  NamespaceSelector{Exclude: []string{}},
+		},
+		{
+			name:  "explicit namespaces pinned",
+			flags: &cliFlags{namespaces: []string{"prod", "staging"}},
+			want:  NamespaceSelector{Namespaces: []string{"prod", "staging"}},
+		},
+		{
+			name:  "default excludes the usual system namespaces",
+			flags: &cliFlags{excludeNS: defaultExcludeNamespaces},
+			want:  NamespaceSelector{Exclude: defaultExcludeNamespaces},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := namespaceSelectorFromFlags(testCase.flags)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestReporterFromFlags(t *testing.T) {
+	testCases := []struct {
+		output    string
+		wantType  Reporter
+		wantError bool
+	}{
+		{"", StdoutReporter{}, false},
+		{"text", StdoutReporter{}, false},
+		{"json", JSONReporter{}, false},
+		{"sarif", SARIFReporter{}, false},
+		{"yaml", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.output, func(t *testing.T) {
+			reporter, err := reporterFromFlags(&cliFlags{output: testCase.output})
+			if testCase.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, testCase.wantType, reporter)
+		})
+	}
+}
+
+func TestPSSProfileFromFlag(t *testing.T) {
+	testCases := []struct {
+		input     string
+		want      PSSProfile
+		wantError bool
+	}{
+		{"baseline", PSSProfileBaseline, false},
+		{"restricted", PSSProfileRestricted, false},
+		{"restriced", "", true},
+		{"", "", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			got, err := pssProfileFromFlag(testCase.input)
+			if testCase.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DetectMode selects how a container's effective user is determined.
+type DetectMode string
+
+const (
+	// DetectModeExec always shells into the container and runs `whoami`.
+	DetectModeExec DetectMode = "exec"
+	// DetectModeSpec resolves the effective user from the pod/container spec
+	// and image metadata only, never execing into the container.
+	DetectModeSpec DetectMode = "spec"
+	// DetectModeHybrid tries DetectModeSpec first and falls back to
+	// DetectModeExec when the spec doesn't resolve the user unambiguously.
+	DetectModeHybrid DetectMode = "hybrid"
+)
+
+// resolveRunAsUser walks container.SecurityContext.RunAsUser ->
+// pod.Spec.SecurityContext.RunAsUser, returning the first UID it finds along
+// with the effective RunAsNonRoot setting (container overrides pod).
+func resolveRunAsUser(pod *v1.Pod, container *v1.Container) (uid *int64, nonRoot *bool) {
+	if container.SecurityContext != nil {
+		if container.SecurityContext.RunAsUser != nil {
+			uid = container.SecurityContext.RunAsUser
+		}
+		if container.SecurityContext.RunAsNonRoot != nil {
+			nonRoot = container.SecurityContext.RunAsNonRoot
+		}
+	}
+	if pod.Spec.SecurityContext != nil {
+		if uid == nil {
+			uid = pod.Spec.SecurityContext.RunAsUser
+		}
+		if nonRoot == nil {
+			nonRoot = pod.Spec.SecurityContext.RunAsNonRoot
+		}
+	}
+	return uid, nonRoot
+}
+
+// imageUser fetches the `User` field from the container's image config,
+// authenticating with the pod's imagePullSecrets via k8schain.
+func imageUser(ctx context.Context, clientset kubernetes.Interface, pod *v1.Pod, image string) (string, error) {
+	keychain, err := k8schain.New(ctx, clientset, k8schain.Options{
+		Namespace:          pod.Namespace,
+		ImagePullSecrets:   imagePullSecretNames(pod.Spec.ImagePullSecrets),
+		ServiceAccountName: pod.Spec.ServiceAccountName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("building keychain for %s: %w", image, err)
+	}
+
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	img, err := remote.Image(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return "", fmt.Errorf("fetching image config for %s: %w", image, err)
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return "", fmt.Errorf("reading config for %s: %w", image, err)
+	}
+
+	return cfg.Config.User, nil
+}
+
+func imagePullSecretNames(refs []v1.LocalObjectReference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// resolveContainerRisk inspects a container's spec (and, if needed, its
+// image metadata) and reports whether it runs as root along with the other
+// privilege-escalation risk flags callers want surfaced on Finding.
+// ambiguous is true when the spec doesn't resolve the effective user and the
+// caller should fall back to an exec-based probe.
+func resolveContainerRisk(ctx context.Context, clientset kubernetes.Interface, pod *v1.Pod, container *v1.Container) (info Finding, ambiguous bool, err error) {
+	info = riskFlagsFromSpec(pod, container)
+
+	uid, nonRoot := resolveRunAsUser(pod, container)
+	info.UID = uid
+	info.RunAsNonRoot = nonRoot
+
+	if uid != nil {
+		info.Root = *uid == 0 && !(nonRoot != nil && *nonRoot)
+		info.DetectedVia = "spec"
+		setRunAsRootFinding(&info)
+		return info, false, nil
+	}
+
+	if nonRoot != nil && *nonRoot {
+		// RunAsNonRoot is true and no UID is pinned: whatever UID the image
+		// runs as, the kubelet refuses to start the container if it resolves
+		// to 0, so it can't be root regardless of image metadata.
+		info.Root = false
+		info.DetectedVia = "spec"
+		return info, false, nil
+	}
+
+	user, err := imageUser(ctx, clientset, pod, container.Image)
+	if err != nil {
+		// Image metadata couldn't be fetched (private registry without
+		// pull secrets, rate limiting, etc.) — let the caller fall back.
+		return info, true, nil
+	}
+
+	resolvedUID, ok := parseImageUser(user)
+	if !ok {
+		// Image declares a named user (e.g. "nonroot") we can't map to a
+		// UID without running something; ambiguous, fall back to exec.
+		return info, true, nil
+	}
+
+	info.UID = &resolvedUID
+	info.Root = resolvedUID == 0 && !(nonRoot != nil && *nonRoot)
+	info.DetectedVia = "spec"
+	setRunAsRootFinding(&info)
+	return info, false, nil
+}
+
+// setRunAsRootFinding fills in the Rule/Severity/Evidence fields for the
+// root-detection path once info.Root and info.UID have been resolved.
+func setRunAsRootFinding(info *Finding) {
+	if !info.Root {
+		return
+	}
+	info.Rule = "runAsRoot"
+	info.Severity = SeverityHigh
+	if info.UID != nil {
+		info.Evidence = fmt.Sprintf("effective UID %d", *info.UID)
+	} else {
+		info.Evidence = "no runAsUser/runAsNonRoot set and image declares no non-root user"
+	}
+}
+
+// riskFlagsFromSpec copies the privilege-escalation related fields off the
+// pod and container spec onto a Finding.
+func riskFlagsFromSpec(pod *v1.Pod, container *v1.Container) Finding {
+	info := Finding{
+		Namespace:   pod.Namespace,
+		PodName:     pod.Name,
+		Container:   container.Name,
+		HostPID:     pod.Spec.HostPID,
+		HostNetwork: pod.Spec.HostNetwork,
+		HostIPC:     pod.Spec.HostIPC,
+	}
+
+	if container.SecurityContext != nil {
+		sc := container.SecurityContext
+		if sc.Privileged != nil {
+			info.Privileged = *sc.Privileged
+		}
+		if sc.AllowPrivilegeEscalation != nil {
+			info.AllowPrivilegeEscalation = *sc.AllowPrivilegeEscalation
+		}
+		if sc.Capabilities != nil {
+			for _, c := range sc.Capabilities.Add {
+				info.AddedCapabilities = append(info.AddedCapabilities, string(c))
+			}
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ephemeralDebugImage is the sidecar injected into distroless/scratch pods
+// that have no shell to exec into.
+const ephemeralDebugImage = "busybox"
+
+// ephemeralDebugTimeout bounds how long we wait for the debug container to
+// start running and report a UID before giving up.
+const ephemeralDebugTimeout = 30 * time.Second
+
+// isShMissingError reports whether err is the "no shell in this image"
+// failure that makes `sh -c whoami` unusable on distroless/scratch images.
+func isShMissingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), `exec: "sh": executable file not found`)
+}
+
+// ephemeralDebugContainerName derives a deterministic name for the debug
+// sidecar targeting containerName, so a rerun recognizes and reuses a debug
+// container from a previous run instead of appending a duplicate (the
+// ephemeralcontainers subresource is append-only).
+func ephemeralDebugContainerName(containerName string) string {
+	name := "root-detector-debug-" + containerName
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return name
+}
+
+// ensureEphemeralDebugContainer returns the name of an ephemeral debug
+// container targeting containerName, reusing one left over from a prior run
+// if present (whatever its state, including exited), otherwise patching a
+// new one onto the pod.
+func ensureEphemeralDebugContainer(ctx context.Context, clientset kubernetes.Interface, namespace, podName, containerName string) (string, error) {
+	debugName := ephemeralDebugContainerName(containerName)
+
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("getting pod %s/%s: %w", namespace, podName, err)
+	}
+
+	for _, ec := range pod.Spec.EphemeralContainers {
+		if ec.Name == debugName {
+			return debugName, nil
+		}
+	}
+
+	debugContainer := v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:    debugName,
+			Image:   ephemeralDebugImage,
+			Command: []string{"sh", "-c", "cat /proc/1/status | awk '$1==\"Uid:\"{print $2}'; sleep 30"},
+		},
+		TargetContainerName: containerName,
+	}
+
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, debugContainer)
+	if _, err := clientset.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{}); err != nil {
+		return "", fmt.Errorf("patching ephemeralcontainers on %s/%s: %w", namespace, podName, err)
+	}
+
+	return debugName, nil
+}
+
+// waitForEphemeralContainerRunning polls the pod until debugName has
+// started (or ctx/timeout expires), so GetLogs doesn't race container
+// startup.
+func waitForEphemeralContainerRunning(ctx context.Context, clientset kubernetes.Interface, namespace, podName, debugName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name == debugName && (status.State.Running != nil || status.State.Terminated != nil) {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for ephemeral container %s to start", debugName)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// readEphemeralDebugUID reads the UID reported by the debug container's
+// logs (see ensureEphemeralDebugContainer's command) for the target
+// container's PID 1.
+func readEphemeralDebugUID(ctx context.Context, clientset kubernetes.Interface, namespace, podName, debugName string) (int64, error) {
+	if err := waitForEphemeralContainerRunning(ctx, clientset, namespace, podName, debugName, ephemeralDebugTimeout); err != nil {
+		return 0, err
+	}
+
+	req := clientset.CoreV1().Pods(namespace).GetLogs(podName, &v1.PodLogOptions{Container: debugName})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("reading logs for ephemeral container %s: %w", debugName, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if uid, err := strconv.ParseInt(line, 10, 64); err == nil {
+			return uid, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("ephemeral container %s produced no parsable UID", debugName)
+}
+
+// detectViaEphemeralDebug is the --use-ephemeral-debug fallback: it injects
+// (or reuses) a busybox sidecar sharing the target container's PID
+// namespace and classifies root vs non-root from the UID it reports for
+// PID 1.
+func detectViaEphemeralDebug(ctx context.Context, clientset kubernetes.Interface, namespace, podName, containerName string) (Finding, error) {
+	debugName, err := ensureEphemeralDebugContainer(ctx, clientset, namespace, podName, containerName)
+	if err != nil {
+		return Finding{}, err
+	}
+
+	uid, err := readEphemeralDebugUID(ctx, clientset, namespace, podName, debugName)
+	if err != nil {
+		return Finding{}, err
+	}
+
+	return Finding{
+		Namespace:   namespace,
+		PodName:     podName,
+		Container:   containerName,
+		UID:         &uid,
+		Root:        uid == 0,
+		DetectedVia: "ephemeral-debug",
+		Evidence:    fmt.Sprintf("ephemeral debug container reported PID 1 UID %d", uid),
+	}, nil
+}
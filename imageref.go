@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// parseImageReference parses a container image reference as used in a pod
+// spec, tolerating the common case where no registry is specified.
+func parseImageReference(image string) (name.Reference, error) {
+	return name.ParseReference(image)
+}
+
+// parseImageUser attempts to interpret an image config's `User` field as a
+// numeric UID (optionally "uid:gid"). An empty User is the documented
+// container-runtime default of UID 0, not an unresolvable value. Named
+// users (e.g. "nonroot") can't be resolved without running the image, so ok
+// is false for those.
+func parseImageUser(user string) (uid int64, ok bool) {
+	if user == "" {
+		return 0, true
+	}
+
+	raw := user
+	if idx := strings.IndexByte(user, ':'); idx >= 0 {
+		raw = user[:idx]
+	}
+
+	parsed, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return parsed, true
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// seenSet is a small bounded LRU of string keys. Watch mode uses it to
+// remember {uid, containerName} pairs it has already reported, so a steady
+// running pod isn't re-announced every resync while a restarted pod (new
+// UID) or a new container name still triggers re-evaluation.
+type seenSet struct {
+	mu      sync.Mutex
+	maxSize int
+	order   *list.List
+	index   map[string]*list.Element
+}
+
+func newSeenSet(maxSize int) *seenSet {
+	return &seenSet{
+		maxSize: maxSize,
+		order:   list.New(),
+		index:   make(map[string]*list.Element),
+	}
+}
+
+// contains reports whether key was previously added, refreshing its
+// recency if so.
+func (s *seenSet) contains(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.index[key]
+	if !ok {
+		return false
+	}
+	s.order.MoveToFront(elem)
+	return true
+}
+
+// add records key as seen, evicting the least recently used entry if the
+// set is over capacity.
+func (s *seenSet) add(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.index[key]; ok {
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.index[key] = s.order.PushFront(key)
+	for s.order.Len() > s.maxSize {
+		oldest := s.order.Back()
+		if oldest == nil {
+			break
+		}
+		s.order.Remove(oldest)
+		delete(s.index, oldest.Value.(string))
+	}
+}
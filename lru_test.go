@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeenSetContainsAndAdd(t *testing.T) {
+	s := newSeenSet(2)
+
+	assert.False(t, s.contains("a"))
+	s.add("a")
+	assert.True(t, s.contains("a"))
+}
+
+func TestSeenSetEvictsLeastRecentlyUsed(t *testing.T) {
+	s := newSeenSet(2)
+
+	s.add("a")
+	s.add("b")
+	// Touch "a" so "b" becomes the least recently used.
+	assert.True(t, s.contains("a"))
+	s.add("c")
+
+	assert.True(t, s.contains("a"))
+	assert.False(t, s.contains("b"), "b should have been evicted")
+	assert.True(t, s.contains("c"))
+}
@@ -2,9 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -14,24 +11,79 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
-	"k8s.io/client-go/util/homedir"
 )
 
-// ContainerInfo stores information about a container.
-type ContainerInfo struct {
+// Finding stores information about a container, including the risk flags
+// pulled from its spec (and, if an exec probe ran, the command used). It
+// covers both the root-detection path (Rule "runAsRoot") and Pod Security
+// Standards violations produced by evaluatePSSPod.
+type Finding struct {
 	Namespace   string
 	PodName     string
 	Container   string
 	CommandExec string
+
+	// Rule identifies the control this Finding reports on, e.g.
+	// "runAsRoot", "hostPID", "privileged". Severity is its relative
+	// importance, and Evidence points at the offending field/value.
+	Rule     string
+	Severity Severity
+	Evidence string
+
+	// Root is true when the container's effective user is (or resolves to)
+	// UID 0 and RunAsNonRoot isn't set to true.
+	Root bool
+	// DetectedVia records how Root was determined: "spec", "exec", or
+	// "ephemeral-debug".
+	DetectedVia string
+
+	UID                      *int64
+	RunAsNonRoot             *bool
+	Privileged               bool
+	AllowPrivilegeEscalation bool
+	HostPID                  bool
+	HostNetwork              bool
+	HostIPC                  bool
+	AddedCapabilities        []string
+
+	// ExecAttempts and LastExecError are populated for containers checked
+	// by exec (DetectedVia "exec", or an errored container whose exec
+	// failed), recording how many tries execCommandInContainerWithRetry made
+	// and the error from the final one (empty on success).
+	ExecAttempts  int
+	LastExecError string
 }
 
-// authenticateToCluster authenticates to the Kubernetes cluster and returns a clientset and config.
-func authenticateToCluster() (*kubernetes.Clientset, *rest.Config, error) {
-	kubeconfigPath := filepath.Join(homedir.HomeDir(), ".kube", "config")
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+// authenticateToCluster authenticates to the Kubernetes cluster and returns
+// a clientset and config. It tries in-cluster config first (so the tool can
+// run as a Deployment, e.g. for --watch), then falls back to the merged
+// kubeconfig loading rules (honoring $KUBECONFIG and the usual search path)
+// with kubeconfigPath and kubeContext overriding the defaults when set.
+func authenticateToCluster(kubeconfigPath, kubeContext string) (*kubernetes.Clientset, *rest.Config, error) {
+	config, err := rest.InClusterConfig()
 	if err != nil {
-		return nil, nil, err
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		if kubeconfigPath != "" {
+			loadingRules.ExplicitPath = kubeconfigPath
+		}
+
+		overrides := &clientcmd.ConfigOverrides{}
+		if kubeContext != "" {
+			overrides.CurrentContext = kubeContext
+		}
+
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
+
+	// The default QPS/Burst (5/10) throttle a parallel scan almost
+	// immediately; raise them so the worker pool can actually run
+	// concurrently. client-go's REST client still honors any 429
+	// Retry-After the apiserver sends on top of this client-side limit.
+	config.QPS = 50
+	config.Burst = 100
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, nil, err
@@ -40,10 +92,10 @@ func authenticateToCluster() (*kubernetes.Clientset, *rest.Config, error) {
 }
 
 // listNamespaces lists all non-excluded namespaces in the cluster.
-func listNamespaces(clientset *kubernetes.Clientset, excludeNamespaces []string) ([]string, error) {
+func listNamespaces(ctx context.Context, clientset kubernetes.Interface, excludeNamespaces []string) ([]string, error) {
 	namespaces := []string{}
 
-	namespaceList, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	namespaceList, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -68,11 +120,15 @@ func containsString(slice []string, str string) bool {
 	return false
 }
 
-// listPods lists all pods in the specified namespace.
-func listPods(clientset *kubernetes.Clientset, namespace string) ([]string, error) {
+// listPods lists pods in the specified namespace matching labelSelector and
+// fieldSelector (either may be empty to match everything).
+func listPods(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector, fieldSelector string) ([]string, error) {
 	podNames := []string{}
 
-	podList, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -85,10 +141,10 @@ func listPods(clientset *kubernetes.Clientset, namespace string) ([]string, erro
 }
 
 // listContainers lists all containers in the specified pod.
-func listContainers(clientset *kubernetes.Clientset, namespace, podName string) ([]string, error) {
+func listContainers(ctx context.Context, clientset kubernetes.Interface, namespace, podName string) ([]string, error) {
 	containerNames := []string{}
 
-	pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +156,9 @@ func listContainers(clientset *kubernetes.Clientset, namespace, podName string)
 	return containerNames, nil
 }
 
-// execCommandInContainer executes a command in the specified container and returns the output.
-func execCommandInContainer(clientset *kubernetes.Clientset, config *rest.Config, namespace, podName, containerName, command string) (string, error) {
+// execCommandInContainer executes a command in the specified container and
+// returns the output. It stops early if ctx is canceled.
+func execCommandInContainer(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, namespace, podName, containerName, command string) (string, error) {
 	cmd := []string{
 		"sh",
 		"-c",
@@ -127,7 +184,7 @@ func execCommandInContainer(clientset *kubernetes.Clientset, config *rest.Config
 	}
 
 	var stdout, stderr strings.Builder
-	err = exec.Stream(remotecommand.StreamOptions{
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
 		Stdout: &stdout,
 		Stderr: &stderr,
 	})
@@ -137,82 +194,3 @@ func execCommandInContainer(clientset *kubernetes.Clientset, config *rest.Config
 
 	return stdout.String(), nil
 }
-
-// findContainersWithErrors finds root containers and lists containers where the command errored based on the specified criteria.
-func findContainersWithErrors(clientset *kubernetes.Clientset, config *rest.Config) ([]ContainerInfo, []ContainerInfo, error) {
-	var rootContainers []ContainerInfo
-	var errorContainers []ContainerInfo
-
-	excludeNamespaces := []string{"kube-system", "kube-public", "kube-node-lease"}
-
-	namespaces, err := listNamespaces(clientset, excludeNamespaces)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	for _, namespace := range namespaces {
-		pods, err := listPods(clientset, namespace)
-		if err != nil {
-			fmt.Printf("Error listing pods in namespace %s: %v\n", namespace, err)
-			continue
-		}
-
-		for _, pod := range pods {
-			containers, err := listContainers(clientset, namespace, pod)
-			if err != nil {
-				fmt.Printf("Error listing containers in pod %s: %v\n", pod, err)
-				continue
-			}
-
-			for _, container := range containers {
-				command := "whoami"
-				output, err := execCommandInContainer(clientset, config, namespace, pod, container, command)
-				if err != nil {
-					fmt.Printf("Error running 'whoami' command in container %s: %v\n", container, err)
-					errorContainers = append(errorContainers, ContainerInfo{
-						Namespace:   namespace,
-						PodName:     pod,
-						Container:   container,
-						CommandExec: command,
-					})
-					continue
-				}
-
-				if strings.Contains(output, "root") {
-					rootContainers = append(rootContainers, ContainerInfo{
-						Namespace:   namespace,
-						PodName:     pod,
-						Container:   container,
-						CommandExec: command,
-					})
-				}
-			}
-		}
-	}
-
-	return rootContainers, errorContainers, nil
-}
-
-func main() {
-	clientset, config, err := authenticateToCluster()
-	if err != nil {
-		fmt.Printf("Error authenticating to the cluster: %v\n", err)
-		os.Exit(1)
-	}
-
-	rootContainers, errorContainers, err := findContainersWithErrors(clientset, config)
-	if err != nil {
-		fmt.Printf("Error finding containers: %v\n", err)
-		os.Exit(1)
-	}
-
-	fmt.Println("\nRoot Containers:")
-	for _, rootContainer := range rootContainers {
-		fmt.Printf("Namespace: %s, Pod: %s, Container: %s, CommandExec: %s\n", rootContainer.Namespace, rootContainer.PodName, rootContainer.Container, rootContainer.CommandExec)
-	}
-
-	fmt.Println("\nContainers with Errors:")
-	for _, errorContainer := range errorContainers {
-		fmt.Printf("Namespace: %s, Pod: %s, Container: %s, CommandExec: %s\n", errorContainer.Namespace, errorContainer.PodName, errorContainer.Container, errorContainer.CommandExec)
-	}
-}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	rootContainersTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "root_containers_total",
+		Help: "Total number of containers observed running as root.",
+	})
+	scanErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "scan_errors_total",
+		Help: "Total number of containers whose root detection errored.",
+	})
+	scanDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "scan_duration_seconds",
+		Help:    "Time taken to evaluate a single pod's containers.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+// serveMetrics starts an HTTP server exposing /metrics for Prometheus
+// scraping. It runs until the process exits; callers in watch mode start
+// it once at startup.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Printf("metrics server stopped: %v\n", err)
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultExcludeNamespaces is used when the caller (CLI or library) doesn't
+// specify its own exclusion list.
+var defaultExcludeNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// NamespaceSelector picks which namespaces a scan or audit covers. The zero
+// value scans every namespace except defaultExcludeNamespaces.
+type NamespaceSelector struct {
+	// Namespaces restricts the scan to exactly these namespaces. Empty means
+	// every namespace not in Exclude.
+	Namespaces []string
+	// Exclude is only consulted when Namespaces is empty; defaults to
+	// defaultExcludeNamespaces when nil.
+	Exclude []string
+}
+
+// resolveNamespaces turns a NamespaceSelector into a concrete namespace
+// list, querying the cluster only when Namespaces wasn't pinned explicitly.
+// A nil Exclude falls back to defaultExcludeNamespaces; pass a non-nil empty
+// slice to exclude nothing (e.g. for --all-namespaces).
+func resolveNamespaces(ctx context.Context, clientset kubernetes.Interface, sel NamespaceSelector) ([]string, error) {
+	if len(sel.Namespaces) > 0 {
+		return sel.Namespaces, nil
+	}
+
+	exclude := sel.Exclude
+	if exclude == nil {
+		exclude = defaultExcludeNamespaces
+	}
+	return listNamespaces(ctx, clientset, exclude)
+}
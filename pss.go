@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// PSSProfile selects which Pod Security Standards level evaluatePSSPod
+// checks against. Restricted includes every Baseline control plus its own.
+type PSSProfile string
+
+const (
+	PSSProfileBaseline   PSSProfile = "baseline"
+	PSSProfileRestricted PSSProfile = "restricted"
+)
+
+// Severity is the relative importance of a Finding.
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+// baselineAllowedCapabilities is the Pod Security Standards Baseline's
+// allow-list for added capabilities (everything else is disallowed).
+var baselineAllowedCapabilities = map[string]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true,
+	"FSETID": true, "KILL": true, "MKNOD": true, "NET_BIND_SERVICE": true,
+	"SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// evaluatePSSPod evaluates a pod against the given Pod Security Standards
+// profile and returns one Finding per violated control, restricted
+// profiles include every baseline violation plus restricted-only ones.
+func evaluatePSSPod(pod *v1.Pod, profile PSSProfile) []Finding {
+	var findings []Finding
+
+	findings = append(findings, evaluateBaselinePod(pod)...)
+	if profile == PSSProfileRestricted {
+		findings = append(findings, evaluateRestrictedPod(pod)...)
+	}
+
+	return findings
+}
+
+func podFinding(pod *v1.Pod, container, rule string, severity Severity, evidence string) Finding {
+	return Finding{
+		Namespace: pod.Namespace,
+		PodName:   pod.Name,
+		Container: container,
+		Rule:      rule,
+		Severity:  severity,
+		Evidence:  evidence,
+	}
+}
+
+// evaluateBaselinePod checks the Baseline profile's host namespace,
+// privileged, and capability controls.
+func evaluateBaselinePod(pod *v1.Pod) []Finding {
+	var findings []Finding
+
+	if pod.Spec.HostNetwork {
+		findings = append(findings, podFinding(pod, "", "hostNetwork", SeverityHigh, "spec.hostNetwork is true"))
+	}
+	if pod.Spec.HostPID {
+		findings = append(findings, podFinding(pod, "", "hostPID", SeverityHigh, "spec.hostPID is true"))
+	}
+	if pod.Spec.HostIPC {
+		findings = append(findings, podFinding(pod, "", "hostIPC", SeverityHigh, "spec.hostIPC is true"))
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			findings = append(findings, podFinding(pod, "", "restrictedVolumes", SeverityMedium,
+				fmt.Sprintf("volume %q uses hostPath %q", volume.Name, volume.HostPath.Path)))
+		}
+	}
+
+	for _, container := range allPodContainers(pod) {
+		sc := container.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			findings = append(findings, podFinding(pod, container.Name, "privileged", SeverityCritical,
+				"securityContext.privileged is true"))
+		}
+		if sc.Capabilities != nil {
+			for _, cap := range sc.Capabilities.Add {
+				if !baselineAllowedCapabilities[string(cap)] {
+					findings = append(findings, podFinding(pod, container.Name, "capabilities", SeverityHigh,
+						fmt.Sprintf("securityContext.capabilities.add includes disallowed capability %q", cap)))
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// evaluateRestrictedPod checks the additional controls the Restricted
+// profile layers on top of Baseline: non-root, no privilege escalation,
+// a seccomp profile, and the default procMount.
+func evaluateRestrictedPod(pod *v1.Pod) []Finding {
+	var findings []Finding
+
+	if !podRequiresNonRoot(pod) {
+		findings = append(findings, podFinding(pod, "", "runAsNonRoot", SeverityHigh,
+			"runAsNonRoot: true is set neither at pod level nor on every container"))
+	}
+
+	if !podHasSeccompProfile(pod) {
+		findings = append(findings, podFinding(pod, "", "seccompProfile", SeverityMedium,
+			"no RuntimeDefault/Localhost seccompProfile set at pod or container level"))
+	}
+
+	for _, container := range allPodContainers(pod) {
+		sc := container.SecurityContext
+		if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			findings = append(findings, podFinding(pod, container.Name, "allowPrivilegeEscalation", SeverityHigh,
+				"securityContext.allowPrivilegeEscalation is not explicitly false"))
+		}
+		if sc != nil && sc.ProcMount != nil && *sc.ProcMount != v1.DefaultProcMount {
+			findings = append(findings, podFinding(pod, container.Name, "procMount", SeverityMedium,
+				fmt.Sprintf("securityContext.procMount is %q, want %q", *sc.ProcMount, v1.DefaultProcMount)))
+		}
+		if !containerDropsAllCapabilities(sc) {
+			findings = append(findings, podFinding(pod, container.Name, "capabilities", SeverityMedium,
+				"securityContext.capabilities.drop does not include ALL"))
+		}
+	}
+
+	return findings
+}
+
+func podRequiresNonRoot(pod *v1.Pod) bool {
+	if pod.Spec.SecurityContext != nil && pod.Spec.SecurityContext.RunAsNonRoot != nil && *pod.Spec.SecurityContext.RunAsNonRoot {
+		return true
+	}
+	containers := allPodContainers(pod)
+	if len(containers) == 0 {
+		return false
+	}
+	for _, container := range containers {
+		sc := container.SecurityContext
+		if sc == nil || sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot {
+			return false
+		}
+	}
+	return true
+}
+
+func podHasSeccompProfile(pod *v1.Pod) bool {
+	if pod.Spec.SecurityContext != nil && isAllowedSeccompProfileType(pod.Spec.SecurityContext.SeccompProfile) {
+		return true
+	}
+	for _, container := range allPodContainers(pod) {
+		if container.SecurityContext != nil && isAllowedSeccompProfileType(container.SecurityContext.SeccompProfile) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAllowedSeccompProfileType(profile *v1.SeccompProfile) bool {
+	return profile != nil &&
+		(profile.Type == v1.SeccompProfileTypeRuntimeDefault || profile.Type == v1.SeccompProfileTypeLocalhost)
+}
+
+func containerDropsAllCapabilities(sc *v1.SecurityContext) bool {
+	if sc == nil || sc.Capabilities == nil {
+		return false
+	}
+	for _, dropped := range sc.Capabilities.Drop {
+		if dropped == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// allPodContainers returns every init, regular, and ephemeral container on
+// a pod, since PSS controls apply uniformly across all three.
+func allPodContainers(pod *v1.Pod) []v1.Container {
+	containers := make([]v1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers)+len(pod.Spec.EphemeralContainers))
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	for _, ec := range pod.Spec.EphemeralContainers {
+		containers = append(containers, v1.Container(ec.EphemeralContainerCommon))
+	}
+	return containers
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestEvaluatePSSPodBaseline(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"},
+		Spec: v1.PodSpec{
+			HostPID: true,
+			Containers: []v1.Container{
+				{
+					Name: "app",
+					SecurityContext: &v1.SecurityContext{
+						Privileged: boolPtr(true),
+						Capabilities: &v1.Capabilities{
+							Add: []v1.Capability{"SYS_ADMIN"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	findings := evaluatePSSPod(pod, PSSProfileBaseline)
+
+	rules := map[string]bool{}
+	for _, f := range findings {
+		rules[f.Rule] = true
+	}
+
+	assert.True(t, rules["hostPID"])
+	assert.True(t, rules["privileged"])
+	assert.True(t, rules["capabilities"])
+	assert.False(t, rules["runAsNonRoot"], "runAsNonRoot is a restricted-only control")
+}
+
+func TestEvaluatePSSPodRestrictedRequiresNonRoot(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "app"}},
+		},
+	}
+
+	findings := evaluatePSSPod(pod, PSSProfileRestricted)
+
+	rules := map[string]bool{}
+	for _, f := range findings {
+		rules[f.Rule] = true
+	}
+
+	assert.True(t, rules["runAsNonRoot"])
+	assert.True(t, rules["allowPrivilegeEscalation"])
+	assert.True(t, rules["seccompProfile"])
+}
+
+func TestEvaluatePSSPodChecksEphemeralContainers(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "app"}},
+			EphemeralContainers: []v1.EphemeralContainer{
+				{
+					EphemeralContainerCommon: v1.EphemeralContainerCommon{
+						Name: "debug",
+						SecurityContext: &v1.SecurityContext{
+							Privileged: boolPtr(true),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	findings := evaluatePSSPod(pod, PSSProfileBaseline)
+
+	var found bool
+	for _, f := range findings {
+		if f.Rule == "privileged" && f.Container == "debug" {
+			found = true
+		}
+	}
+	assert.True(t, found, "expected a privileged finding on the ephemeral container \"debug\"")
+}
+
+func TestEvaluatePSSPodRestrictedCompliant(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"},
+		Spec: v1.PodSpec{
+			SecurityContext: &v1.PodSecurityContext{
+				RunAsNonRoot:   boolPtr(true),
+				SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []v1.Container{
+				{
+					Name: "app",
+					SecurityContext: &v1.SecurityContext{
+						RunAsNonRoot:             boolPtr(true),
+						AllowPrivilegeEscalation: boolPtr(false),
+						Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}},
+					},
+				},
+			},
+		},
+	}
+
+	findings := evaluatePSSPod(pod, PSSProfileRestricted)
+	assert.Empty(t, findings)
+}
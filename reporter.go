@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Reporter emits a set of Findings in some output format.
+type Reporter interface {
+	Report(findings []Finding) error
+}
+
+// StdoutReporter prints Findings as human-readable lines, one per Finding,
+// giving its namespace, pod, container, rule, severity, and evidence.
+type StdoutReporter struct {
+	Writer io.Writer
+}
+
+func (r StdoutReporter) Report(findings []Finding) error {
+	for _, f := range findings {
+		_, err := fmt.Fprintf(r.Writer, "Namespace: %s, Pod: %s, Container: %s, Rule: %s, Severity: %s, Evidence: %s\n",
+			f.Namespace, f.PodName, f.Container, f.Rule, f.Severity, f.Evidence)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONReporter writes Findings as a single JSON array.
+type JSONReporter struct {
+	Writer io.Writer
+}
+
+func (r JSONReporter) Report(findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	enc := json.NewEncoder(r.Writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// sarifLog, sarifRun, sarifResult, etc. model just enough of the SARIF
+// 2.1.0 schema to plug into GitHub code scanning and similar dashboards.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// SARIFReporter writes Findings as a SARIF 2.1.0 log with one result per
+// Finding, so code-scanning tools can ingest it directly.
+type SARIFReporter struct {
+	Writer io.Writer
+}
+
+func (r SARIFReporter) Report(findings []Finding) error {
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: sarifDriver{Name: "root-detector", Rules: sarifRulesFor(findings)}},
+				Results: make([]sarifResult, 0, len(findings)),
+			},
+		},
+	}
+
+	for _, f := range findings {
+		location := f.Namespace + "/" + f.PodName
+		if f.Container != "" {
+			location += "/" + f.Container
+		}
+		log.Runs[0].Results = append(log.Runs[0].Results, sarifResult{
+			RuleID:  f.Rule,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Evidence},
+			Locations: []sarifLocation{
+				{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: location}}},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(r.Writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+func sarifRulesFor(findings []Finding) []sarifRule {
+	seen := map[string]bool{}
+	var rules []sarifRule
+	for _, f := range findings {
+		if !seen[f.Rule] {
+			seen[f.Rule] = true
+			rules = append(rules, sarifRule{ID: f.Rule})
+		}
+	}
+	return rules
+}
+
+// sarifLevel maps our Severity onto SARIF's note/warning/error levels.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
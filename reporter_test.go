@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONReporter(t *testing.T) {
+	findings := []Finding{
+		{Namespace: "default", PodName: "test-pod", Container: "app", Rule: "privileged", Severity: SeverityCritical, Evidence: "securityContext.privileged is true"},
+	}
+
+	var buf bytes.Buffer
+	err := JSONReporter{Writer: &buf}.Report(findings)
+	assert.NoError(t, err)
+
+	var decoded []Finding
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, findings, decoded)
+}
+
+func TestSARIFReporter(t *testing.T) {
+	findings := []Finding{
+		{Namespace: "default", PodName: "test-pod", Container: "app", Rule: "privileged", Severity: SeverityCritical, Evidence: "securityContext.privileged is true"},
+	}
+
+	var buf bytes.Buffer
+	err := SARIFReporter{Writer: &buf}.Report(findings)
+	assert.NoError(t, err)
+
+	var decoded sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "2.1.0", decoded.Version)
+	assert.Len(t, decoded.Runs, 1)
+	assert.Len(t, decoded.Runs[0].Results, 1)
+	assert.Equal(t, "privileged", decoded.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "error", decoded.Runs[0].Results[0].Level)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ExecRetryConfig controls how execCommandInContainerWithRetry retries a
+// failed exec.
+type ExecRetryConfig struct {
+	// MaxExecRetries is the maximum number of attempts (including the
+	// first). Defaults to 5 via DefaultExecRetryConfig.
+	MaxExecRetries int
+	// ExecTimeout bounds a single exec attempt.
+	ExecTimeout time.Duration
+	// BackoffBase is the base delay doubled on each retry, then jittered.
+	BackoffBase time.Duration
+}
+
+// DefaultExecRetryConfig mirrors the e2e framework's maxKubectlExecRetries:
+// a handful of attempts with a short exponential backoff is enough to ride
+// out apiserver hiccups without masking a genuinely broken container.
+func DefaultExecRetryConfig() ExecRetryConfig {
+	return ExecRetryConfig{
+		MaxExecRetries: 5,
+		ExecTimeout:    10 * time.Second,
+		BackoffBase:    250 * time.Millisecond,
+	}
+}
+
+// execCommandInContainerWithRetry retries execCommandInContainer with
+// exponential backoff and jitter, but only for errors classified as
+// transient by isRetryableExecError. It returns the number of attempts made
+// and the error from the final attempt (nil on success) alongside the
+// output, so the caller can record both on Finding.
+func execCommandInContainerWithRetry(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, namespace, podName, containerName, command string, podPhase v1.PodPhase, cfg ExecRetryConfig) (output string, attempts int, lastErr error) {
+	for attempts = 1; attempts <= cfg.MaxExecRetries; attempts++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.ExecTimeout)
+		output, lastErr = execCommandInContainer(attemptCtx, clientset, config, namespace, podName, containerName, command)
+		cancel()
+
+		if lastErr == nil {
+			return output, attempts, nil
+		}
+		if !isRetryableExecError(lastErr, podPhase) {
+			return "", attempts, lastErr
+		}
+		if attempts == cfg.MaxExecRetries {
+			break
+		}
+
+		select {
+		case <-time.After(backoffDelay(cfg.BackoffBase, attempts)):
+		case <-ctx.Done():
+			return "", attempts, ctx.Err()
+		}
+	}
+
+	return "", attempts, lastErr
+}
+
+// backoffDelay returns base*2^(attempt-1) plus up to 50% jitter.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << uint(attempt-1)
+	jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+	return delay + jitter
+}
+
+// isRetryableExecError classifies exec failures as transient (worth
+// retrying) or permanent. Forbidden (RBAC) and "no shell in the image"
+// errors short-circuit immediately; SPDY/HTTP2 stream errors, TLS handshake
+// timeouts, and a missing container while the pod is still Pending are
+// retried.
+func isRetryableExecError(err error, podPhase v1.PodPhase) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	switch {
+	case strings.Contains(msg, "forbidden"):
+		return false
+	case strings.Contains(msg, "executable file not found"):
+		return false
+	case strings.Contains(msg, "stream error"), strings.Contains(msg, "stream reset"):
+		return true
+	case strings.Contains(msg, "http2: stream closed"):
+		return true
+	case strings.Contains(msg, "tls handshake timeout"):
+		return true
+	case strings.Contains(msg, "container not found") && podPhase == v1.PodPending:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestIsRetryableExecError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		podPhase v1.PodPhase
+		want     bool
+	}{
+		{"nil error", nil, v1.PodRunning, false},
+		{"forbidden is permanent", errors.New("pods/exec is forbidden: User cannot exec"), v1.PodRunning, false},
+		{"no shell is permanent", errors.New(`exec: "sh": executable file not found in $PATH`), v1.PodRunning, false},
+		{"SPDY stream error is retryable", errors.New("stream error when reading response body"), v1.PodRunning, true},
+		{"SPDY stream reset is retryable", errors.New("stream reset by peer"), v1.PodRunning, true},
+		{"http2 stream closed is retryable", errors.New("http2: stream closed"), v1.PodRunning, true},
+		{"TLS handshake timeout is retryable", errors.New("net/http: TLS handshake timeout"), v1.PodRunning, true},
+		{"container not found while pending is retryable", errors.New("container not found"), v1.PodPending, true},
+		{"container not found while running is permanent", errors.New("container not found"), v1.PodRunning, false},
+		{"unrecognized error is permanent", errors.New("some other failure"), v1.PodRunning, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := isRetryableExecError(testCase.err, testCase.podPhase)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		delay := backoffDelay(base, attempt)
+		minDelay := base << uint(attempt-1)
+		maxDelay := minDelay + minDelay/2
+
+		assert.GreaterOrEqualf(t, delay, minDelay, "attempt %d", attempt)
+		assert.LessOrEqualf(t, delay, maxDelay, "attempt %d", attempt)
+	}
+}
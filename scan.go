@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// podListPageSize bounds how many pods are fetched per List call when
+// paging through a namespace, so a single namespace with a huge number of
+// pods doesn't pull them all into memory at once.
+const podListPageSize = 500
+
+// containerJob is one container to evaluate, queued onto the worker pool.
+type containerJob struct {
+	namespace string
+	pod       v1.Pod
+	container v1.Container
+}
+
+// ScanOptions configures a cluster scan. The zero value isn't ready to use;
+// call DefaultScanOptions and override individual fields.
+type ScanOptions struct {
+	// Mode selects how the effective user is resolved (see DetectMode).
+	Mode DetectMode
+	// Concurrency is the worker-pool size; <= 0 uses defaultConcurrency().
+	Concurrency int
+	// UseEphemeralDebug enables the ephemeral-debug-container fallback for
+	// containers whose exec fails because the image has no shell.
+	UseEphemeralDebug bool
+	// ExecRetry configures execCommandInContainerWithRetry.
+	ExecRetry ExecRetryConfig
+	// Namespaces selects which namespaces are scanned; see NamespaceSelector.
+	Namespaces NamespaceSelector
+	// LabelSelector and FieldSelector are passed through to each pod List
+	// call, restricting the scan to matching pods. Empty matches everything.
+	LabelSelector string
+	FieldSelector string
+}
+
+// DefaultScanOptions returns hybrid detection, a CPU-scaled worker pool, no
+// ephemeral-debug fallback, and DefaultExecRetryConfig.
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		Mode:              DetectModeHybrid,
+		Concurrency:       0,
+		UseEphemeralDebug: false,
+		ExecRetry:         DefaultExecRetryConfig(),
+	}
+}
+
+// defaultConcurrency returns a worker-pool size scaled to the host when the
+// caller doesn't request a specific one.
+func defaultConcurrency() int {
+	return runtime.NumCPU() * 4
+}
+
+// findContainersWithErrors scans every non-excluded namespace and reports
+// root containers and containers whose detection errored, per opts.
+// Container checks run across a pool of opts.Concurrency workers; ctx
+// cancellation (e.g. Ctrl-C) stops the scan and returns promptly.
+func findContainersWithErrors(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, opts ScanOptions) ([]Finding, []Finding, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency()
+	}
+
+	namespaces, err := resolveNamespaces(ctx, clientset, opts.Namespaces)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jobs := make(chan containerJob, concurrency)
+	results := make(chan Finding)
+	errored := make(chan Finding)
+
+	var producerErr error
+	var producerWg sync.WaitGroup
+	producerWg.Add(1)
+	go func() {
+		defer producerWg.Done()
+		defer close(jobs)
+		producerErr = produceContainerJobs(ctx, clientset, namespaces, opts.LabelSelector, opts.FieldSelector, jobs)
+	}()
+
+	var workerWg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workerWg.Add(1)
+		go func() {
+			defer workerWg.Done()
+			for job := range jobs {
+				evaluateContainer(ctx, clientset, config, opts, job, results, errored)
+			}
+		}()
+	}
+
+	go func() {
+		workerWg.Wait()
+		close(results)
+		close(errored)
+	}()
+
+	var rootContainers []Finding
+	var errorContainers []Finding
+	var collectWg sync.WaitGroup
+	collectWg.Add(2)
+	go func() {
+		defer collectWg.Done()
+		for info := range results {
+			rootContainers = append(rootContainers, info)
+		}
+	}()
+	go func() {
+		defer collectWg.Done()
+		for info := range errored {
+			errorContainers = append(errorContainers, info)
+		}
+	}()
+
+	producerWg.Wait()
+	collectWg.Wait()
+
+	if producerErr != nil {
+		return nil, nil, producerErr
+	}
+	if ctx.Err() != nil {
+		return rootContainers, errorContainers, ctx.Err()
+	}
+
+	return rootContainers, errorContainers, nil
+}
+
+// produceContainerJobs lists pods namespace-by-namespace, paging through
+// each with ListOptions.Limit/Continue, and sends one containerJob per
+// container onto jobs. It stops (without error) as soon as ctx is done.
+func produceContainerJobs(ctx context.Context, clientset kubernetes.Interface, namespaces []string, labelSelector, fieldSelector string, jobs chan<- containerJob) error {
+	for _, namespace := range namespaces {
+		continueToken := ""
+		for {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+				Limit:         podListPageSize,
+				Continue:      continueToken,
+				LabelSelector: labelSelector,
+				FieldSelector: fieldSelector,
+			})
+			if err != nil {
+				fmt.Printf("Error listing pods in namespace %s: %v\n", namespace, err)
+				break
+			}
+
+			for _, pod := range podList.Items {
+				for i := range pod.Spec.Containers {
+					select {
+					case jobs <- containerJob{namespace: namespace, pod: pod, container: pod.Spec.Containers[i]}:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+			}
+
+			continueToken = podList.Continue
+			if continueToken == "" {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// evaluateContainer runs the spec/exec/ephemeral-debug detection for a
+// single job and publishes the result onto results (root containers) or
+// errored (containers whose detection failed).
+func evaluateContainer(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, opts ScanOptions, job containerJob, results, errored chan<- Finding) {
+	pod := job.pod
+	container := job.container
+
+	if opts.Mode != DetectModeExec {
+		info, ambiguous, err := resolveContainerRisk(ctx, clientset, &pod, &container)
+		if err == nil && !ambiguous {
+			if info.Root {
+				results <- info
+			}
+			return
+		}
+		if opts.Mode == DetectModeSpec {
+			// Spec-only mode never falls back to exec; an unresolved
+			// container is simply not reported.
+			return
+		}
+	}
+
+	command := "whoami"
+	output, attempts, err := execCommandInContainerWithRetry(ctx, clientset, config, job.namespace, pod.Name, container.Name, command, pod.Status.Phase, opts.ExecRetry)
+	if err != nil {
+		if opts.UseEphemeralDebug && isShMissingError(err) {
+			if info, debugErr := detectViaEphemeralDebug(ctx, clientset, job.namespace, pod.Name, container.Name); debugErr == nil {
+				info = mergeRiskFlags(info, riskFlagsFromSpec(&pod, &container))
+				if info.Root {
+					info.Rule = "runAsRoot"
+					info.Severity = SeverityHigh
+					results <- info
+				}
+				return
+			}
+		}
+
+		fmt.Printf("Error running 'whoami' command in container %s after %d attempt(s): %v\n", container.Name, attempts, err)
+		errored <- Finding{
+			Namespace:     job.namespace,
+			PodName:       pod.Name,
+			Container:     container.Name,
+			CommandExec:   command,
+			ExecAttempts:  attempts,
+			LastExecError: err.Error(),
+		}
+		return
+	}
+
+	info := riskFlagsFromSpec(&pod, &container)
+	info.CommandExec = command
+	info.DetectedVia = "exec"
+	info.ExecAttempts = attempts
+	if strings.Contains(output, "root") {
+		info.Root = true
+		info.Rule = "runAsRoot"
+		info.Severity = SeverityHigh
+		info.Evidence = "whoami reported root"
+		results <- info
+	}
+}
+
+// mergeRiskFlags layers the privilege-escalation risk flags from spec onto
+// a Finding produced by a user-resolution path (exec or ephemeral
+// debug) that doesn't itself inspect the pod/container spec.
+func mergeRiskFlags(info, spec Finding) Finding {
+	info.Privileged = spec.Privileged
+	info.AllowPrivilegeEscalation = spec.AllowPrivilegeEscalation
+	info.HostPID = spec.HostPID
+	info.HostNetwork = spec.HostNetwork
+	info.HostIPC = spec.HostIPC
+	info.AddedCapabilities = spec.AddedCapabilities
+	return info
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int64Ptr(i int64) *int64 { return &i }
+
+// TestEvaluateContainerSpecModeResolvesWithoutExec exercises the spec-only
+// path: a pinned RunAsUser resolves the container unambiguously, so
+// evaluateContainer must report it without ever falling back to exec (which
+// would hang here, since the fake clientset can't serve the exec
+// subresource).
+func TestEvaluateContainerSpecModeResolvesWithoutExec(t *testing.T) {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "root-pod"},
+	}
+	container := v1.Container{
+		Name:            "app",
+		SecurityContext: &v1.SecurityContext{RunAsUser: int64Ptr(0)},
+	}
+
+	clientset := fake.NewSimpleClientset()
+	opts := ScanOptions{Mode: DetectModeSpec}
+	job := containerJob{namespace: "default", pod: pod, container: container}
+	results := make(chan Finding, 1)
+	errored := make(chan Finding, 1)
+
+	evaluateContainer(context.Background(), clientset, nil, opts, job, results, errored)
+	close(results)
+	close(errored)
+
+	findings := []Finding{}
+	for f := range results {
+		findings = append(findings, f)
+	}
+	assert.Len(t, findings, 1)
+	assert.True(t, findings[0].Root)
+	assert.Equal(t, "spec", findings[0].DetectedVia)
+	assert.Empty(t, errored)
+}
+
+// TestEvaluateContainerHybridModeDropsNonRoot covers the other unambiguous
+// spec outcome in hybrid mode: RunAsNonRoot true means the container can't
+// be root regardless of the image, so it's resolved (not ambiguous) but
+// never published since it isn't a finding.
+func TestEvaluateContainerHybridModeDropsNonRoot(t *testing.T) {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nonroot-pod"},
+	}
+	container := v1.Container{
+		Name:            "app",
+		SecurityContext: &v1.SecurityContext{RunAsNonRoot: boolPtr(true)},
+	}
+
+	clientset := fake.NewSimpleClientset()
+	opts := ScanOptions{Mode: DetectModeHybrid}
+	job := containerJob{namespace: "default", pod: pod, container: container}
+	results := make(chan Finding, 1)
+	errored := make(chan Finding, 1)
+
+	evaluateContainer(context.Background(), clientset, nil, opts, job, results, errored)
+	close(results)
+	close(errored)
+
+	assert.Empty(t, results)
+	assert.Empty(t, errored)
+}
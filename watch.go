@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// defaultResyncPeriod is how often the shared informer re-lists everything
+// it's watching, catching anything a missed watch event would otherwise
+// leave stale.
+const defaultResyncPeriod = 10 * time.Minute
+
+// seenSetSize bounds the watch mode LRU of already-reported containers.
+const seenSetSize = 50000
+
+// WatchOptions configures RunWatch.
+type WatchOptions struct {
+	// Mode selects how the effective user is resolved (see DetectMode).
+	Mode DetectMode
+	// UseEphemeralDebug enables the ephemeral-debug-container fallback for
+	// containers whose exec fails because the image has no shell.
+	UseEphemeralDebug bool
+	// Reporter receives each newly observed root/privileged container as
+	// it's found, in real time.
+	Reporter Reporter
+	// ResyncPeriod is how often the informer redelivers every pod, not just
+	// ones with new watch events. Containers already evaluated (per the seen
+	// set) are skipped on resync; only ones not yet evaluated, e.g. after an
+	// earlier error, are checked again. Defaults to defaultResyncPeriod.
+	ResyncPeriod time.Duration
+	// MetricsAddr, if non-empty, serves Prometheus metrics (e.g. ":8080").
+	MetricsAddr string
+
+	// Namespace scopes the underlying informer to a single namespace; empty
+	// watches every namespace. The informer API can't watch an arbitrary set
+	// of namespaces directly, so multiple --namespace values are instead
+	// applied as an IncludeNamespaces filter on top of a cluster-wide watch.
+	Namespace string
+	// IncludeNamespaces, if non-empty, reports only pods in these
+	// namespaces. ExcludeNamespaces is only consulted when it's empty.
+	IncludeNamespaces []string
+	// ExcludeNamespaces filters out pods in these namespaces.
+	ExcludeNamespaces []string
+	// LabelSelector and FieldSelector restrict which pods the informer
+	// lists and watches.
+	LabelSelector string
+	FieldSelector string
+}
+
+// DefaultWatchOptions returns sane defaults for RunWatch.
+func DefaultWatchOptions() WatchOptions {
+	return WatchOptions{
+		Mode:         DetectModeHybrid,
+		Reporter:     StdoutReporter{Writer: os.Stdout},
+		ResyncPeriod: defaultResyncPeriod,
+	}
+}
+
+// RunWatch runs a long-lived, cluster-wide watch over Pods using a shared
+// informer (mirroring the waitForPodReady pattern used in tests, but
+// generalized to every pod rather than one). Each pod is evaluated as soon
+// as it reaches PodRunning; a periodic resync re-checks any container not
+// yet recorded in the seen set. It
+// blocks until ctx is canceled, making it suitable to run as an in-cluster
+// Deployment.
+func RunWatch(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, opts WatchOptions) error {
+	if opts.ResyncPeriod <= 0 {
+		opts.ResyncPeriod = defaultResyncPeriod
+	}
+	if opts.Reporter == nil {
+		opts.Reporter = StdoutReporter{Writer: os.Stdout}
+	}
+	if opts.MetricsAddr != "" {
+		serveMetrics(opts.MetricsAddr)
+	}
+
+	seen := newSeenSet(seenSetSize)
+
+	tweakListOptions := func(listOptions *metav1.ListOptions) {
+		listOptions.LabelSelector = opts.LabelSelector
+		listOptions.FieldSelector = opts.FieldSelector
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, opts.ResyncPeriod,
+		informers.WithNamespace(opts.Namespace),
+		informers.WithTweakListOptions(tweakListOptions),
+	)
+	podInformer := factory.Core().V1().Pods().Informer()
+
+	handle := func(obj interface{}) {
+		pod, ok := obj.(*v1.Pod)
+		if !ok || pod.Status.Phase != v1.PodRunning || !watchIncludesNamespace(opts, pod.Namespace) {
+			return
+		}
+		evaluatePodForWatch(ctx, clientset, config, opts, pod, seen)
+	}
+
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    handle,
+		UpdateFunc: func(oldObj, newObj interface{}) { handle(newObj) },
+	})
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), podInformer.HasSynced) {
+		return fmt.Errorf("timed out waiting for the pod informer cache to sync")
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// watchIncludesNamespace applies IncludeNamespaces/ExcludeNamespaces on top
+// of the informer's own (single-namespace) scoping, so multiple --namespace
+// values can still be honored against a cluster-wide watch.
+func watchIncludesNamespace(opts WatchOptions, namespace string) bool {
+	if len(opts.IncludeNamespaces) > 0 {
+		return containsString(opts.IncludeNamespaces, namespace)
+	}
+	return !containsString(opts.ExcludeNamespaces, namespace)
+}
+
+// evaluatePodForWatch checks each of a running pod's containers that
+// hasn't already been reported (per the {uid, containerName} seen set) and
+// reports any that are root.
+func evaluatePodForWatch(ctx context.Context, clientset kubernetes.Interface, config *rest.Config, opts WatchOptions, pod *v1.Pod, seen *seenSet) {
+	start := time.Now()
+	defer func() { scanDurationSeconds.Observe(time.Since(start).Seconds()) }()
+
+	scanOpts := ScanOptions{Mode: opts.Mode, UseEphemeralDebug: opts.UseEphemeralDebug, ExecRetry: DefaultExecRetryConfig()}
+
+	for i := range pod.Spec.Containers {
+		container := pod.Spec.Containers[i]
+		key := fmt.Sprintf("%s/%s", pod.UID, container.Name)
+		if seen.contains(key) {
+			continue
+		}
+
+		results := make(chan Finding, 1)
+		errored := make(chan Finding, 1)
+		evaluateContainer(ctx, clientset, config, scanOpts, containerJob{namespace: pod.Namespace, pod: *pod, container: container}, results, errored)
+		close(results)
+		close(errored)
+
+		hadError := false
+		for info := range results {
+			rootContainersTotal.Inc()
+			if err := opts.Reporter.Report([]Finding{info}); err != nil {
+				fmt.Printf("Error reporting finding for %s/%s/%s: %v\n", info.Namespace, info.PodName, info.Container, err)
+			}
+		}
+		for range errored {
+			hadError = true
+			scanErrorsTotal.Inc()
+		}
+
+		// Only remember this container once it's actually been evaluated
+		// (root or not); a hard error (e.g. retries exhausted) leaves it
+		// unseen so the next resync retries it instead of never checking
+		// again.
+		if !hadError {
+			seen.add(key)
+		}
+	}
+}